@@ -0,0 +1,135 @@
+package sys
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// ArchiveFS is a read-only FS whose contents are the entries of an archive (see NewTarFS and
+// NewZipFS), loaded eagerly into an in-memory tree the same way a FakeFS is. This lets callers
+// read a bundle directly from an io.Reader (e.g. an http.Response.Body or an embed.FS entry)
+// without unpacking it to disk first.
+type ArchiveFS struct {
+	tree *FakeFS
+}
+
+// FP returns an FP that walks the archive's entries in lexical order, the same way FakeFP
+// walks a FakeFS.
+func (a *ArchiveFS) FP() FP {
+	return &FakeFP{FakeFS: *a.tree}
+}
+
+// ReadFile is the FS interface implementation.
+func (a *ArchiveFS) ReadFile(name string) ([]byte, error) {
+	return a.tree.ReadFile(name)
+}
+
+// Stat is the FS interface implementation.
+func (a *ArchiveFS) Stat(name string) (os.FileInfo, error) {
+	return a.tree.Stat(name)
+}
+
+// RemoveAll is the FS interface implementation. An ArchiveFS is read-only, so it always
+// returns ErrReadOnly.
+func (a *ArchiveFS) RemoveAll(name string) error {
+	return ErrReadOnly{Op: "removeall"}
+}
+
+// Create is the FS interface implementation. An ArchiveFS is read-only, so it always returns
+// ErrReadOnly.
+func (a *ArchiveFS) Create(name string) (io.WriteCloser, error) {
+	return nil, ErrReadOnly{Op: "create"}
+}
+
+// MkdirAll is the FS interface implementation. An ArchiveFS is read-only, so it always returns
+// ErrReadOnly.
+func (a *ArchiveFS) MkdirAll(name string, perm os.FileMode) error {
+	return ErrReadOnly{Op: "mkdirall"}
+}
+
+// WriteFile is the FS interface implementation. An ArchiveFS is read-only, so it always
+// returns ErrReadOnly.
+func (a *ArchiveFS) WriteFile(name string, data []byte, perm os.FileMode) (int, error) {
+	return 0, ErrReadOnly{Op: "writefile"}
+}
+
+// Open is the FS interface implementation.
+func (a *ArchiveFS) Open(name string) (File, error) {
+	return a.tree.Open(name)
+}
+
+// OpenFile is the FS interface implementation. An ArchiveFS is read-only, so any flag other
+// than os.O_RDONLY returns ErrReadOnly.
+func (a *ArchiveFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag != os.O_RDONLY {
+		return nil, ErrReadOnly{Op: "openfile"}
+	}
+	return a.tree.OpenFile(name, flag, perm)
+}
+
+// ConfirmDir is the FS interface implementation.
+func (a *ArchiveFS) ConfirmDir(name string) (ConfirmedDir, error) {
+	return a.tree.ConfirmDir(name)
+}
+
+// NewTmpConfirmedDir is the FS interface implementation. An ArchiveFS is read-only, so it
+// always returns ErrReadOnly.
+func (a *ArchiveFS) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return "", ErrReadOnly{Op: "newtmpconfirmeddir"}
+}
+
+// NewTarFS reads the tar archive in r and returns its contents as a read-only FS.
+func NewTarFS(r io.Reader) (FS, error) {
+	tree := NewFakeFS()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			tree.AddDir(hdr.Name)
+		case tar.TypeReg:
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			tree.AddFile(hdr.Name, data, hdr.FileInfo().Mode())
+		}
+	}
+	return &ArchiveFS{tree: tree}, nil
+}
+
+// NewZipFS reads the zip archive in r (of the given size) and returns its contents as a
+// read-only FS.
+func NewZipFS(r io.ReaderAt, size int64) (FS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	tree := NewFakeFS()
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			tree.AddDir(zf.Name)
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		tree.AddFile(zf.Name, data, zf.Mode())
+	}
+	return &ArchiveFS{tree: tree}, nil
+}
@@ -0,0 +1,85 @@
+package sys
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestNewTarFSReadsEntries(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "dir", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatalf("WriteHeader(dir): %v", err)
+	}
+	contents := []byte("hello from tar")
+	if err := tw.WriteHeader(&tar.Header{Name: "dir/f.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(contents))}); err != nil {
+		t.Fatalf("WriteHeader(file): %v", err)
+	}
+	if _, err := tw.Write(contents); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	fsys, err := NewTarFS(&buf)
+	if err != nil {
+		t.Fatalf("NewTarFS: %v", err)
+	}
+
+	got, err := fsys.ReadFile("dir/f.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(contents) {
+		t.Errorf("ReadFile = %q, want %q", got, contents)
+	}
+
+	fi, err := fsys.Stat("dir")
+	if err != nil {
+		t.Fatalf("Stat(dir): %v", err)
+	}
+	if !fi.IsDir() {
+		t.Errorf("Stat(dir).IsDir() = false, want true")
+	}
+
+	if _, err := fsys.Create("new.txt"); err == nil {
+		t.Error("Create on an ArchiveFS succeeded, want ErrReadOnly")
+	}
+}
+
+func TestNewZipFSReadsEntries(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("a.txt")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	contents := []byte("hello from zip")
+	if _, err := w.Write(contents); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	fsys, err := NewZipFS(r, int64(r.Len()))
+	if err != nil {
+		t.Fatalf("NewZipFS: %v", err)
+	}
+
+	got, err := fsys.ReadFile("a.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(contents) {
+		t.Errorf("ReadFile = %q, want %q", got, contents)
+	}
+
+	if err := fsys.MkdirAll("b", 0755); err == nil {
+		t.Error("MkdirAll on an ArchiveFS succeeded, want ErrReadOnly")
+	}
+}
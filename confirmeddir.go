@@ -0,0 +1,91 @@
+package sys
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ConfirmedDir is a clean, absolute, symlink-resolved path that was confirmed, at the time it
+// was created by FS.ConfirmDir or FS.NewTmpConfirmedDir, to point at an existing directory.
+// Borrowed from kustomize, it gives callers a typed way to say "this is a real directory",
+// without every caller having to reimplement the clean/eval/stat dance (or getting it wrong
+// and opening up a path-traversal bug).
+type ConfirmedDir string
+
+// Join joins elems onto d the same way filepath.Join does.
+func (d ConfirmedDir) Join(elems ...string) string {
+	return filepath.Join(append([]string{string(d)}, elems...)...)
+}
+
+// HasPrefix returns true if d is other, or a descendant of other, using path-boundary
+// semantics rather than raw string comparison; e.g. ConfirmedDir("/foo/bar").HasPrefix("/foo/ba")
+// is false even though the two strings share a textual prefix.
+func (d ConfirmedDir) HasPrefix(other ConfirmedDir) bool {
+	if d == other {
+		return true
+	}
+	if other == ConfirmedDir(filepath.Separator) {
+		return strings.HasPrefix(string(d), string(other))
+	}
+	return strings.HasPrefix(string(d), string(other)+string(filepath.Separator))
+}
+
+// ConfirmDir is the interface implementation for FS. It resolves path to a clean, absolute,
+// symlink-resolved path and confirms that it's an existing directory.
+func (r *realFS) ConfirmDir(path string) (ConfirmedDir, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", resolved)
+	}
+	return ConfirmedDir(resolved), nil
+}
+
+// NewTmpConfirmedDir is the interface implementation for FS. It creates a new directory under
+// the system's temp directory and returns it as a ConfirmedDir.
+func (r *realFS) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	dir, err := ioutil.TempDir("", "sys-")
+	if err != nil {
+		return "", err
+	}
+	return r.ConfirmDir(dir)
+}
+
+// ConfirmDir is the interface implementation for FS. It resolves p against f's in-memory tree
+// and confirms that it's an existing directory node.
+func (f *FakeFS) ConfirmDir(p string) (ConfirmedDir, error) {
+	clean := path.Clean(p)
+	if !path.IsAbs(clean) {
+		clean = path.Join("/", clean)
+	}
+	node := f.lookup(clean)
+	if node == nil || !node.isDir() {
+		return "", FakeFileNotFound{Filename: p}
+	}
+	return ConfirmedDir(clean), nil
+}
+
+// NewTmpConfirmedDir is the interface implementation for FS. It creates a new, uniquely named
+// directory node under /tmp in f's in-memory tree.
+func (f *FakeFS) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	f.tmpCount++
+	p := path.Join("/tmp", fmt.Sprintf("fake-dir-%d", f.tmpCount))
+	if _, err := f.mkdirAll(p, 0755); err != nil {
+		return "", err
+	}
+	return ConfirmedDir(p), nil
+}
@@ -0,0 +1,63 @@
+package sys
+
+import "testing"
+
+func TestConfirmedDirJoin(t *testing.T) {
+	d := ConfirmedDir("/foo/bar")
+	if got, want := d.Join("baz", "qux.txt"), "/foo/bar/baz/qux.txt"; got != want {
+		t.Errorf("Join = %q, want %q", got, want)
+	}
+}
+
+func TestConfirmedDirHasPrefix(t *testing.T) {
+	cases := []struct {
+		d, other ConfirmedDir
+		want     bool
+	}{
+		{"/foo/bar", "/foo/bar", true},
+		{"/foo/bar/baz", "/foo/bar", true},
+		{"/foo/bar", "/foo/ba", false},
+		{"/foo/barbaz", "/foo/bar", false},
+		{"/foo", "/foo/bar", false},
+		{"/foo", "/", true},
+		{"/", "/", true},
+	}
+	for _, c := range cases {
+		if got := c.d.HasPrefix(c.other); got != c.want {
+			t.Errorf("%q.HasPrefix(%q) = %v, want %v", c.d, c.other, got, c.want)
+		}
+	}
+}
+
+func TestFakeFSConfirmDir(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/a/b/c.txt", []byte("x"), 0644)
+
+	if _, err := fsys.ConfirmDir("/a/b"); err != nil {
+		t.Errorf("ConfirmDir(dir): %v", err)
+	}
+	if _, err := fsys.ConfirmDir("/a/b/c.txt"); err == nil {
+		t.Error("ConfirmDir(file) succeeded, want error")
+	}
+	if _, err := fsys.ConfirmDir("/nope"); err == nil {
+		t.Error("ConfirmDir(missing) succeeded, want error")
+	}
+}
+
+func TestFakeFSNewTmpConfirmedDirIsUnique(t *testing.T) {
+	fsys := NewFakeFS()
+
+	d1, err := fsys.NewTmpConfirmedDir()
+	if err != nil {
+		t.Fatalf("NewTmpConfirmedDir: %v", err)
+	}
+	d2, err := fsys.NewTmpConfirmedDir()
+	if err != nil {
+		t.Fatalf("NewTmpConfirmedDir: %v", err)
+	}
+	if d1 == d2 {
+		t.Errorf("NewTmpConfirmedDir returned the same dir twice: %q", d1)
+	}
+	if _, err := fsys.ConfirmDir(string(d1)); err != nil {
+		t.Errorf("ConfirmDir(%q): %v", d1, err)
+	}
+}
@@ -1,11 +1,18 @@
 package sys
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
-// FakeFI represents a fake os.FileInfo struct
+// FakeFI represents a fake os.FileInfo struct, backed by the metadata of a node in a FakeFS's
+// in-memory tree.
 type FakeFI struct {
-	os.FileInfo
-	isDir bool
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+	isDir   bool
 }
 
 // NewFakeFI returns a FakeFI.
@@ -13,6 +20,26 @@ func NewFakeFI() *FakeFI {
 	return &FakeFI{}
 }
 
+// Name returns the base name of the file.
+func (ffi *FakeFI) Name() string {
+	return ffi.name
+}
+
+// Size returns the length in bytes of the file.
+func (ffi *FakeFI) Size() int64 {
+	return ffi.size
+}
+
+// Mode returns the file mode bits.
+func (ffi *FakeFI) Mode() os.FileMode {
+	return ffi.mode
+}
+
+// ModTime returns the file's modification time.
+func (ffi *FakeFI) ModTime() time.Time {
+	return ffi.modTime
+}
+
 // IsDir returns the isDir bool value on a FakeFI instance
 func (ffi *FakeFI) IsDir() bool {
 	return ffi.isDir
@@ -22,3 +49,8 @@ func (ffi *FakeFI) IsDir() bool {
 func (ffi *FakeFI) SetIsDir(isDir bool) {
 	ffi.isDir = isDir
 }
+
+// Sys returns nil. FakeFI doesn't carry any OS-specific data.
+func (ffi *FakeFI) Sys() interface{} {
+	return nil
+}
@@ -0,0 +1,26 @@
+package sys
+
+import (
+	"io"
+	"os"
+)
+
+// File is a file handle returned by FS.Open and FS.OpenFile. It supports random-access reads,
+// writes and seeks, the same shape used by tsuru/fs and afero-derived libraries, so code that
+// needs to seek, read partially, or reopen a file for append can be written against FS.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Seeker
+	io.Closer
+	io.ReaderAt
+
+	// Name returns the name of the file as given to FS.Open or FS.OpenFile.
+	Name() string
+
+	// Stat returns the os.FileInfo describing the file.
+	Stat() (os.FileInfo, error)
+
+	// Truncate changes the size of the file.
+	Truncate(size int64) error
+}
@@ -0,0 +1,125 @@
+package sys
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestFakeFSOpenReadsExistingFile(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/a.txt", []byte("hello"), 0644)
+
+	f, err := fsys.Open("/a.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("ReadAll = %q, want %q", got, "hello")
+	}
+
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Write on an os.O_RDONLY handle succeeded, want error")
+	}
+}
+
+func TestFakeFSOpenFileCreateAndAppend(t *testing.T) {
+	fsys := NewFakeFS()
+
+	f, err := fsys.OpenFile("/a.txt", os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile(create): %v", err)
+	}
+	if _, err := f.Write([]byte("hello ")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f.Close()
+
+	f2, err := fsys.OpenFile("/a.txt", os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile(append): %v", err)
+	}
+	if _, err := f2.Write([]byte("world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f2.Close()
+
+	got, err := fsys.ReadFile("/a.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "hello world" {
+		t.Errorf("ReadFile = %q, want %q", got, "hello world")
+	}
+}
+
+func TestFakeFSOpenFileSeekAndReadAt(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/a.txt", []byte("0123456789"), 0644)
+
+	f, err := fsys.Open("/a.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	b := make([]byte, 3)
+	if _, err := f.Read(b); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(b) != "567" {
+		t.Errorf("Read after Seek = %q, want %q", b, "567")
+	}
+
+	b2 := make([]byte, 4)
+	if _, err := f.ReadAt(b2, 2); err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if string(b2) != "2345" {
+		t.Errorf("ReadAt = %q, want %q", b2, "2345")
+	}
+}
+
+func TestFakeFSOpenFileTruncate(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/a.txt", []byte("0123456789"), 0644)
+
+	f, err := fsys.OpenFile("/a.txt", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if err := f.Truncate(3); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+	f.Close()
+
+	got, err := fsys.ReadFile("/a.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "012" {
+		t.Errorf("ReadFile after Truncate = %q, want %q", got, "012")
+	}
+}
+
+func TestFakeFSOpenMissingFileErrors(t *testing.T) {
+	fsys := NewFakeFS()
+
+	if _, err := fsys.Open("/nope.txt"); err == nil {
+		t.Error("Open of a missing file succeeded, want error")
+	}
+}
+
+func TestFakeFSOpenDirectoryErrors(t *testing.T) {
+	fsys := NewFakeFS().AddDir("/sub")
+
+	if _, err := fsys.Open("/sub"); err == nil {
+		t.Error("Open of a directory succeeded, want error")
+	}
+}
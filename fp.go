@@ -1,6 +1,11 @@
 package sys
 
-import "path/filepath"
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
 
 // FP is the interface to a filepath.
 type FP interface {
@@ -20,7 +25,7 @@ func (r *realFP) Walk(root string, walkFunc filepath.WalkFunc) error {
 	return filepath.Walk(root, walkFunc)
 }
 
-// FakeFP represents a fake filepath
+// FakeFP represents a fake filepath, backed by a FakeFS.
 type FakeFP struct {
 	FakeFS
 	walkInvoked bool
@@ -28,12 +33,53 @@ type FakeFP struct {
 
 // NewFakeFP returns a FakeFP.
 func NewFakeFP() *FakeFP {
-	return &FakeFP{}
+	return &FakeFP{FakeFS: *NewFakeFS()}
 }
 
-// Walk walks the file tree rooted at root, calling walkFn for each file or directory in the tree, including root.
-// Additionally, it sets the f.walkInvoked bool as true
+// Walk walks the file tree rooted at root, calling walkFunc for each file or directory in the
+// tree (including root) in lexical order, the same way filepath.Walk does, honoring
+// filepath.SkipDir when it's returned from walkFunc. Additionally, it sets f.walkInvoked to
+// true.
 func (f *FakeFP) Walk(root string, walkFunc filepath.WalkFunc) error {
 	f.walkInvoked = true
-	return walkFunc(root, NewFakeFI(), nil)
+	node := f.lookup(root)
+	if node == nil {
+		return walkFunc(root, nil, os.ErrNotExist)
+	}
+	if err := f.walk(root, node, walkFunc); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// walk visits node (reached via p), then recurses into its children in lexical order.
+func (f *FakeFP) walk(p string, node *fakeNode, walkFunc filepath.WalkFunc) error {
+	err := walkFunc(p, newFakeFI(node), nil)
+	if err != nil {
+		if err == filepath.SkipDir && node.isDir() {
+			return nil
+		}
+		return err
+	}
+	if !node.isDir() {
+		return nil
+	}
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := node.children[name]
+		if err := f.walk(path.Join(p, name), child, walkFunc); err != nil {
+			if err == filepath.SkipDir && !child.isDir() {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
 }
@@ -1,11 +1,13 @@
 package sys
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
+	"strings"
+	"time"
 )
 
 // FS is the interface to a file system.
@@ -29,6 +31,20 @@ type FS interface {
 
 	// WriteFile invokes the func of the same name in the os package (https://godoc.org/io/ioutil#WriteFile).
 	WriteFile(string, []byte, os.FileMode) (int, error)
+
+	// Open opens name for reading, returning a File that supports seeking and partial reads.
+	Open(name string) (File, error)
+
+	// OpenFile invokes the func of the same name in the os package (https://godoc.org/os#OpenFile).
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+
+	// ConfirmDir resolves path to a clean, absolute, symlink-resolved path and confirms that
+	// it's an existing directory, returning it as a ConfirmedDir.
+	ConfirmDir(path string) (ConfirmedDir, error)
+
+	// NewTmpConfirmedDir creates a new, uniquely named temporary directory and returns it as a
+	// ConfirmedDir.
+	NewTmpConfirmedDir() (ConfirmedDir, error)
 }
 
 // RealFS returns an FS object that interacts with the real local filesystem.
@@ -63,6 +79,16 @@ func (r *realFS) Create(path string) (io.WriteCloser, error) {
 	return os.Create(path)
 }
 
+// Open is the interface implementation for FS.
+func (r *realFS) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+// OpenFile is the interface implementation for FS.
+func (r *realFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
 // WriteFile is the interface implementation for FS.
 func (r *realFS) WriteFile(filename string, data []byte, perm os.FileMode) (int, error) {
 	return len(data), ioutil.WriteFile(filename, data, perm)
@@ -78,76 +104,388 @@ func (f FakeFileNotFound) Error() string {
 	return fmt.Sprintf("Fake file %s not found", f.Filename)
 }
 
-// FakeFS is an in-memory FS implementation.
+// FakeNotADirectory is the error returned by FakeFS when a path component that's required to
+// be a directory already exists as a file.
+type FakeNotADirectory struct {
+	Filename string
+}
+
+// Error is the error interface implementation.
+func (f FakeNotADirectory) Error() string {
+	return fmt.Sprintf("Fake file %s is not a directory", f.Filename)
+}
+
+// FakeIsADirectory is the error returned by FakeFS when an operation that requires a file
+// target finds an existing directory there instead.
+type FakeIsADirectory struct {
+	Filename string
+}
+
+// Error is the error interface implementation.
+func (f FakeIsADirectory) Error() string {
+	return fmt.Sprintf("Fake file %s is a directory", f.Filename)
+}
+
+// fakeFileData is a file node's backing storage. It's a pointer so that every File handle
+// opened against the same node observes the other's writes.
+type fakeFileData struct {
+	b []byte
+}
+
+// fakeNode is a single node, either a file or a directory, in a FakeFS's in-memory tree.
+// children is non-nil for directories and nil for files; content is the inverse.
+type fakeNode struct {
+	name     string
+	mode     os.FileMode
+	modTime  time.Time
+	parent   *fakeNode
+	content  *fakeFileData
+	children map[string]*fakeNode
+}
+
+func newFakeDirNode(name string, parent *fakeNode, perm os.FileMode) *fakeNode {
+	return &fakeNode{
+		name:     name,
+		mode:     os.ModeDir | perm,
+		modTime:  time.Now(),
+		parent:   parent,
+		children: make(map[string]*fakeNode),
+	}
+}
+
+func (n *fakeNode) isDir() bool {
+	return n.children != nil
+}
+
+func (n *fakeNode) size() int64 {
+	if n.content == nil {
+		return 0
+	}
+	return int64(len(n.content.b))
+}
+
+// FakeFS is an in-memory FS implementation, backed by a tree of directory and file nodes.
 type FakeFS struct {
-	Files map[string]*bytes.Buffer
+	root     *fakeNode
+	tmpCount int
 }
 
 // NewFakeFS returns a FakeFS with no files.
 func NewFakeFS() *FakeFS {
-	return &FakeFS{Files: make(map[string]*bytes.Buffer)}
+	return &FakeFS{root: newFakeDirNode("/", nil, 0755)}
 }
 
-type inMemoryCloser struct {
-	buf *bytes.Buffer
+// splitPath cleans p and splits it into its non-empty path segments.
+func splitPath(p string) []string {
+	p = path.Clean(p)
+	if p == "." || p == "/" || p == "" {
+		return nil
+	}
+	p = strings.TrimPrefix(p, "/")
+	return strings.Split(p, "/")
 }
 
-func (i inMemoryCloser) Write(b []byte) (int, error) {
-	return i.buf.Write(b)
+// lookup returns the node at p, or nil if no such node exists.
+func (f *FakeFS) lookup(p string) *fakeNode {
+	node := f.root
+	for _, part := range splitPath(p) {
+		if !node.isDir() {
+			return nil
+		}
+		child, ok := node.children[part]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	return node
 }
 
-func (i inMemoryCloser) Close() error {
-	return nil
+// mkdirAll creates every missing directory node along p (including p itself) and returns the
+// node at p. It returns FakeNotADirectory if a path component already exists as a file.
+func (f *FakeFS) mkdirAll(p string, perm os.FileMode) (*fakeNode, error) {
+	node := f.root
+	acc := ""
+	for _, part := range splitPath(p) {
+		acc = path.Join(acc, part)
+		child, ok := node.children[part]
+		if !ok {
+			child = newFakeDirNode(part, node, perm)
+			node.children[part] = child
+		} else if !child.isDir() {
+			return nil, FakeNotADirectory{Filename: acc}
+		}
+		node = child
+	}
+	return node, nil
 }
 
 // ReadFile is the FS interface implementation. It returns FakeFileNotFound if the file was not
-// found in the in-memory 'filesystem' of f.
+// found in the in-memory tree of f.
 func (f *FakeFS) ReadFile(name string) ([]byte, error) {
-	buf, ok := f.Files[name]
-	if !ok {
+	node := f.lookup(name)
+	if node == nil || node.isDir() {
 		return nil, FakeFileNotFound{Filename: name}
 	}
-	return buf.Bytes(), nil
+	return node.content.b, nil
 }
 
-// RemoveAll is the interface implementation for FS.
+// RemoveAll is the interface implementation for FS. It recursively removes the node at name,
+// along with its entire subtree if it's a directory.
 func (f *FakeFS) RemoveAll(name string) error {
-	_, ok := f.Files[name]
-	if !ok {
+	node := f.lookup(name)
+	if node == nil {
 		return FakeFileNotFound{Filename: name}
 	}
-	delete(f.Files, name)
+	if node.parent == nil {
+		f.root = newFakeDirNode("/", nil, 0755)
+		return nil
+	}
+	delete(node.parent.children, node.name)
 	return nil
 }
 
-// Stat is the interface implementation for FS.  It returns os.ErrNotExist if the file was not
-// found in the in-memory 'filesystem' of f
+// Stat is the interface implementation for FS. It returns os.ErrNotExist if the path was not
+// found in the in-memory tree of f.
 func (f *FakeFS) Stat(path string) (os.FileInfo, error) {
-	_, err := f.ReadFile(path)
-	if err != nil {
+	node := f.lookup(path)
+	if node == nil {
 		return nil, os.ErrNotExist
 	}
-	return NewFakeFI(), nil
+	return newFakeFI(node), nil
 }
 
 // MkdirAll is the interface implementation for FS.
 func (f *FakeFS) MkdirAll(dirName string, perm os.FileMode) error {
-	_, err := f.Create(dirName)
+	_, err := f.mkdirAll(dirName, perm)
 	return err
 }
 
-// Create is the interface implementation for FS.  It populates an entry in f.Files for path
-// with an empty byte array and returns an empty os.File struct.
-func (f *FakeFS) Create(path string) (io.WriteCloser, error) {
-	buf := new(bytes.Buffer)
-	f.Files[path] = buf
-	return inMemoryCloser{buf: buf}, nil
+// Create is the interface implementation for FS. It populates a file node at path with an
+// empty buffer, creating any missing intermediate directories. It returns FakeIsADirectory if
+// path already exists as a directory.
+func (f *FakeFS) Create(p string) (io.WriteCloser, error) {
+	dir, name := path.Split(path.Clean(p))
+	parent, err := f.mkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := parent.children[name]; ok && existing.isDir() {
+		return nil, FakeIsADirectory{Filename: p}
+	}
+	data := &fakeFileData{}
+	node := &fakeNode{name: name, mode: 0644, modTime: time.Now(), parent: parent, content: data}
+	parent.children[name] = node
+	return &fakeFileHandle{name: p, node: node, flag: os.O_WRONLY}, nil
 }
 
-// WriteFile is the interface implementation for FS.  To properly emulate WriteFile, it
-// creates a new bytes.Buffer for the value Files[filename] references, then writes data
-// to the this buffer.
+// WriteFile is the interface implementation for FS. It creates a new file node at filename
+// (replacing any that was already there) with data as its contents, creating any missing
+// intermediate directories. It returns FakeIsADirectory if filename already exists as a
+// directory.
 func (f *FakeFS) WriteFile(filename string, data []byte, perm os.FileMode) (int, error) {
-	f.Files[filename] = new(bytes.Buffer)
-	return f.Files[filename].Write(data)
+	dir, name := path.Split(path.Clean(filename))
+	parent, err := f.mkdirAll(dir, 0755)
+	if err != nil {
+		return 0, err
+	}
+	if existing, ok := parent.children[name]; ok && existing.isDir() {
+		return 0, FakeIsADirectory{Filename: filename}
+	}
+	parent.children[name] = &fakeNode{
+		name:    name,
+		mode:    perm,
+		modTime: time.Now(),
+		parent:  parent,
+		content: &fakeFileData{b: append([]byte(nil), data...)},
+	}
+	return len(data), nil
+}
+
+// AddDir adds an empty directory at p, along with any missing intermediate directories, and
+// returns f so calls can be chained. It's meant for declaratively building up FakeFS fixtures
+// in tests.
+func (f *FakeFS) AddDir(p string) *FakeFS {
+	_, _ = f.mkdirAll(p, 0755)
+	return f
+}
+
+// AddFile adds a file at p with the given contents and mode, along with any missing
+// intermediate directories, and returns f so calls can be chained. It's meant for
+// declaratively building up FakeFS fixtures in tests.
+func (f *FakeFS) AddFile(p string, contents []byte, mode os.FileMode) *FakeFS {
+	dir, name := path.Split(path.Clean(p))
+	parent, err := f.mkdirAll(dir, 0755)
+	if err != nil {
+		return f
+	}
+	parent.children[name] = &fakeNode{
+		name:    name,
+		mode:    mode,
+		modTime: time.Now(),
+		parent:  parent,
+		content: &fakeFileData{b: append([]byte(nil), contents...)},
+	}
+	return f
+}
+
+// Open is the interface implementation for FS. It opens name for reading.
+func (f *FakeFS) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile is the interface implementation for FS. It honors os.O_CREATE, os.O_APPEND,
+// os.O_TRUNC and the os.O_RDONLY/os.O_WRONLY/os.O_RDWR access modes, returning a handle whose
+// Read, Write and Seek operate on the node's in-memory backing byte slice.
+func (f *FakeFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	node := f.lookup(name)
+	if node == nil {
+		if flag&os.O_CREATE == 0 {
+			return nil, FakeFileNotFound{Filename: name}
+		}
+		dir, base := path.Split(path.Clean(name))
+		parent, err := f.mkdirAll(dir, 0755)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := parent.children[base]; ok && existing.isDir() {
+			return nil, FakeIsADirectory{Filename: name}
+		}
+		node = &fakeNode{name: base, mode: perm, modTime: time.Now(), parent: parent, content: &fakeFileData{}}
+		parent.children[base] = node
+	}
+	if node.isDir() {
+		return nil, fmt.Errorf("%s is a directory", name)
+	}
+	if flag&os.O_TRUNC != 0 {
+		node.content.b = nil
+	}
+	offset := int64(0)
+	if flag&os.O_APPEND != 0 {
+		offset = int64(len(node.content.b))
+	}
+	return &fakeFileHandle{name: name, node: node, flag: flag, offset: offset}, nil
+}
+
+// fakeFileHandle is a File implementation backed by a fakeNode's in-memory byte slice.
+type fakeFileHandle struct {
+	name   string
+	node   *fakeNode
+	flag   int
+	offset int64
+}
+
+// Name is the File interface implementation.
+func (h *fakeFileHandle) Name() string {
+	return h.name
+}
+
+// Stat is the File interface implementation.
+func (h *fakeFileHandle) Stat() (os.FileInfo, error) {
+	return newFakeFI(h.node), nil
+}
+
+// Read is the File interface implementation.
+func (h *fakeFileHandle) Read(p []byte) (int, error) {
+	if h.flag&os.O_WRONLY != 0 {
+		return 0, fmt.Errorf("%s: file not open for reading", h.name)
+	}
+	b := h.node.content.b
+	if h.offset >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[h.offset:])
+	h.offset += int64(n)
+	return n, nil
+}
+
+// ReadAt is the File interface implementation.
+func (h *fakeFileHandle) ReadAt(p []byte, off int64) (int, error) {
+	if h.flag&os.O_WRONLY != 0 {
+		return 0, fmt.Errorf("%s: file not open for reading", h.name)
+	}
+	b := h.node.content.b
+	if off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Write is the File interface implementation.
+func (h *fakeFileHandle) Write(p []byte) (int, error) {
+	if h.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return 0, fmt.Errorf("%s: file not open for writing", h.name)
+	}
+	b := h.node.content.b
+	if h.flag&os.O_APPEND != 0 {
+		h.offset = int64(len(b))
+	}
+	end := h.offset + int64(len(p))
+	if end > int64(len(b)) {
+		grown := make([]byte, end)
+		copy(grown, b)
+		b = grown
+	}
+	copy(b[h.offset:end], p)
+	h.node.content.b = b
+	h.offset = end
+	h.node.modTime = time.Now()
+	return len(p), nil
+}
+
+// Seek is the File interface implementation.
+func (h *fakeFileHandle) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+		base = 0
+	case io.SeekCurrent:
+		base = h.offset
+	case io.SeekEnd:
+		base = int64(len(h.node.content.b))
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	h.offset = base + offset
+	return h.offset, nil
+}
+
+// Truncate is the File interface implementation.
+func (h *fakeFileHandle) Truncate(size int64) error {
+	if h.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return fmt.Errorf("%s: file not open for writing", h.name)
+	}
+	b := h.node.content.b
+	if size <= int64(len(b)) {
+		h.node.content.b = b[:size]
+		return nil
+	}
+	grown := make([]byte, size)
+	copy(grown, b)
+	h.node.content.b = grown
+	return nil
+}
+
+// Close is the File interface implementation.
+func (h *fakeFileHandle) Close() error {
+	return nil
+}
+
+// newFakeFI builds a FakeFI from the metadata of node.
+func newFakeFI(node *fakeNode) *FakeFI {
+	mode := node.mode
+	if node.isDir() {
+		mode |= os.ModeDir
+	}
+	return &FakeFI{
+		name:    node.name,
+		size:    node.size(),
+		mode:    mode,
+		modTime: node.modTime,
+		isDir:   node.isDir(),
+	}
 }
@@ -0,0 +1,131 @@
+package sys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeFSAddFileAndReadFile(t *testing.T) {
+	fs := NewFakeFS().AddDir("/a/b").AddFile("/a/b/c.txt", []byte("hello"), 0644)
+
+	got, err := fs.ReadFile("/a/b/c.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("ReadFile = %q, want %q", got, "hello")
+	}
+
+	fi, err := fs.Stat("/a/b/c.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.IsDir() {
+		t.Errorf("Stat(%q).IsDir() = true, want false", "/a/b/c.txt")
+	}
+	if fi.Size() != int64(len("hello")) {
+		t.Errorf("Stat(%q).Size() = %d, want %d", "/a/b/c.txt", fi.Size(), len("hello"))
+	}
+
+	dfi, err := fs.Stat("/a/b")
+	if err != nil {
+		t.Fatalf("Stat(dir): %v", err)
+	}
+	if !dfi.IsDir() {
+		t.Errorf("Stat(%q).IsDir() = false, want true", "/a/b")
+	}
+}
+
+func TestFakeFSMkdirAllOverExistingFile(t *testing.T) {
+	fs := NewFakeFS().AddFile("/data", []byte("im a file"), 0644)
+
+	if err := fs.MkdirAll("/data/sub", 0755); err == nil {
+		t.Fatal("MkdirAll over an existing file succeeded, want error")
+	}
+
+	got, err := fs.ReadFile("/data")
+	if err != nil {
+		t.Fatalf("ReadFile after failed MkdirAll: %v", err)
+	}
+	if string(got) != "im a file" {
+		t.Errorf("file contents were clobbered: got %q", got)
+	}
+}
+
+func TestFakeFSCreateOverExistingDirectory(t *testing.T) {
+	fs := NewFakeFS().AddFile("/data/sub/f.txt", []byte("keep me"), 0644)
+
+	if _, err := fs.Create("/data"); err == nil {
+		t.Fatal("Create over an existing, populated directory succeeded, want error")
+	}
+
+	got, err := fs.ReadFile("/data/sub/f.txt")
+	if err != nil {
+		t.Fatalf("ReadFile after failed Create: %v", err)
+	}
+	if string(got) != "keep me" {
+		t.Errorf("directory subtree was clobbered: got %q", got)
+	}
+}
+
+func TestFakeFSWriteFileOverExistingDirectory(t *testing.T) {
+	fs := NewFakeFS().AddFile("/data/sub/f.txt", []byte("keep me"), 0644)
+
+	if _, err := fs.WriteFile("/data", []byte("oops"), 0644); err == nil {
+		t.Fatal("WriteFile over an existing, populated directory succeeded, want error")
+	}
+
+	if _, err := fs.ReadFile("/data/sub/f.txt"); err != nil {
+		t.Fatalf("ReadFile after failed WriteFile: %v", err)
+	}
+}
+
+func TestFakeFSRemoveAllRemovesSubtree(t *testing.T) {
+	fs := NewFakeFS().AddFile("/a/b/c.txt", []byte("x"), 0644)
+
+	if err := fs.RemoveAll("/a"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := fs.Stat("/a/b/c.txt"); err == nil {
+		t.Error("file under removed directory still exists")
+	}
+}
+
+func TestFakeFPWalkLexicalOrder(t *testing.T) {
+	fp := NewFakeFP()
+	fp.AddFile("/root/b.txt", []byte("b"), 0644)
+	fp.AddFile("/root/a.txt", []byte("a"), 0644)
+	fp.AddDir("/root/sub")
+
+	var visited []string
+	err := fp.Walk("/root", func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	want := []string{"/root", "/root/a.txt", "/root/b.txt", "/root/sub"}
+	if len(visited) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", visited, want)
+	}
+	for i, p := range want {
+		if visited[i] != p {
+			t.Errorf("Walk visited[%d] = %q, want %q", i, visited[i], p)
+		}
+	}
+}
+
+func TestFakeFPWalkSkipDirAtRoot(t *testing.T) {
+	fp := NewFakeFP()
+	fp.AddFile("/root.txt", []byte("x"), 0644)
+
+	err := fp.Walk("/root.txt", func(path string, info os.FileInfo, err error) error {
+		return filepath.SkipDir
+	})
+	if err != nil {
+		t.Fatalf("Walk = %v, want nil", err)
+	}
+}
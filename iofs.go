@@ -0,0 +1,324 @@
+package sys
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// ErrReadOnly is returned by the write methods (Create, MkdirAll, WriteFile, RemoveAll,
+// NewTmpConfirmedDir, and OpenFile with any flag other than os.O_RDONLY) of the read-only FS
+// implementations in this package: the FS returned by FromIOFS, since a fs.FS only ever
+// supports reading, and ArchiveFS.
+type ErrReadOnly struct {
+	Op string
+}
+
+// Error is the error interface implementation.
+func (e ErrReadOnly) Error() string {
+	return fmt.Sprintf("%s: read-only filesystem", e.Op)
+}
+
+// ioFSAdapter adapts an FS to the io/fs interfaces introduced in Go 1.16 (fs.FS,
+// fs.ReadFileFS, fs.StatFS, fs.ReadDirFS and fs.GlobFS), so that a RealFS or FakeFS can be
+// passed to anything that expects a fs.FS, such as fs.WalkDir, http.FS or template.ParseFS.
+type ioFSAdapter struct {
+	fsys FS
+}
+
+// AsIOFS adapts fsys to fs.FS (along with fs.ReadFileFS, fs.StatFS, fs.ReadDirFS and
+// fs.GlobFS), so it can be passed to any function that accepts the standard library's fs.FS.
+func AsIOFS(fsys FS) fs.FS {
+	return &ioFSAdapter{fsys: fsys}
+}
+
+// Open is the fs.FS interface implementation. If name is a directory, the returned fs.File
+// also implements fs.ReadDirFile, the same way os.Open's result does, so that consumers like
+// http.FS and fs.WalkDir can list and serve directories, not just regular files. Since that
+// relies on ReadDir, opening a directory fails for any FS other than RealFS and FakeFS.
+func (a *ioFSAdapter) Open(name string) (fs.File, error) {
+	fi, err := a.fsys.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if fi.IsDir() {
+		entries, err := a.ReadDir(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &ioFSDir{fi: fi, entries: entries}, nil
+	}
+	b, err := a.fsys.ReadFile(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &ioFSFile{fi: fi, r: bytes.NewReader(b)}, nil
+}
+
+// ReadFile is the fs.ReadFileFS interface implementation.
+func (a *ioFSAdapter) ReadFile(name string) ([]byte, error) {
+	return a.fsys.ReadFile(name)
+}
+
+// Stat is the fs.StatFS interface implementation.
+func (a *ioFSAdapter) Stat(name string) (fs.FileInfo, error) {
+	return a.fsys.Stat(name)
+}
+
+// ReadDir is the fs.ReadDirFS interface implementation. Directory listing is only meaningful
+// for filesystems that actually track directory structure, so it's implemented only for
+// RealFS and FakeFS and returns fs.ErrInvalid for any other FS implementation, including this
+// package's ArchiveFS, MountFS and overlay FS.
+func (a *ioFSAdapter) ReadDir(name string) ([]fs.DirEntry, error) {
+	var infos []fs.FileInfo
+	switch fsys := a.fsys.(type) {
+	case *realFS:
+		is, err := ioutil.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = is
+	case *FakeFS:
+		is, err := fsys.readDir(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = is
+	default:
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries := make([]fs.DirEntry, len(infos))
+	for i, fi := range infos {
+		entries[i] = dirEntry{fi: fi}
+	}
+	return entries, nil
+}
+
+// Glob is the fs.GlobFS interface implementation. Like ReadDir, it's only implemented for
+// RealFS and FakeFS, and returns fs.ErrInvalid for any other FS implementation.
+func (a *ioFSAdapter) Glob(pattern string) ([]string, error) {
+	switch fsys := a.fsys.(type) {
+	case *realFS:
+		return filepath.Glob(pattern)
+	case *FakeFS:
+		return fsys.glob(pattern)
+	default:
+		return nil, &fs.PathError{Op: "glob", Path: pattern, Err: fs.ErrInvalid}
+	}
+}
+
+// ioFSFile adapts an in-memory byte slice and os.FileInfo to fs.File.
+type ioFSFile struct {
+	fi fs.FileInfo
+	r  *bytes.Reader
+}
+
+func (f *ioFSFile) Stat() (fs.FileInfo, error) { return f.fi, nil }
+func (f *ioFSFile) Read(b []byte) (int, error) { return f.r.Read(b) }
+func (f *ioFSFile) Close() error               { return nil }
+
+// ioFSDir adapts a directory's os.FileInfo and pre-read entries to fs.ReadDirFile, so that
+// ioFSAdapter.Open can return a usable handle for directories, matching what os.Open does for
+// a directory path and what consumers like http.FS and fs.WalkDir expect.
+type ioFSDir struct {
+	fi      fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *ioFSDir) Stat() (fs.FileInfo, error) { return d.fi, nil }
+func (d *ioFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.fi.Name(), Err: fs.ErrInvalid}
+}
+func (d *ioFSDir) Close() error { return nil }
+
+// ReadDir is the fs.ReadDirFile interface implementation.
+func (d *ioFSDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset += len(rest)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
+
+// ioFSSeekableFile adapts an in-memory byte slice and fs.FileInfo to this package's File
+// interface, so the read-only contents of a fs.FS can be opened via FS.Open/FS.OpenFile.
+type ioFSSeekableFile struct {
+	name string
+	fi   fs.FileInfo
+	r    *bytes.Reader
+}
+
+func (f *ioFSSeekableFile) Name() string                            { return f.name }
+func (f *ioFSSeekableFile) Stat() (os.FileInfo, error)              { return f.fi, nil }
+func (f *ioFSSeekableFile) Read(p []byte) (int, error)              { return f.r.Read(p) }
+func (f *ioFSSeekableFile) ReadAt(p []byte, off int64) (int, error) { return f.r.ReadAt(p, off) }
+func (f *ioFSSeekableFile) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+func (f *ioFSSeekableFile) Write(p []byte) (int, error) { return 0, ErrReadOnly{Op: "write"} }
+func (f *ioFSSeekableFile) Close() error                { return nil }
+func (f *ioFSSeekableFile) Truncate(size int64) error   { return ErrReadOnly{Op: "truncate"} }
+
+// dirEntry adapts a fs.FileInfo to fs.DirEntry.
+type dirEntry struct {
+	fi fs.FileInfo
+}
+
+func (d dirEntry) Name() string               { return d.fi.Name() }
+func (d dirEntry) IsDir() bool                { return d.fi.IsDir() }
+func (d dirEntry) Type() fs.FileMode          { return d.fi.Mode().Type() }
+func (d dirEntry) Info() (fs.FileInfo, error) { return d.fi, nil }
+
+// ioFSWrapper adapts a fs.FS to this package's FS interface, so that an embed.FS, os.DirFS or
+// fstest.MapFS can be used anywhere an FS is expected. Since a fs.FS only supports reading,
+// the write methods all return ErrReadOnly.
+type ioFSWrapper struct {
+	fsys fs.FS
+}
+
+// FromIOFS adapts fsys to FS.
+func FromIOFS(fsys fs.FS) FS {
+	return &ioFSWrapper{fsys: fsys}
+}
+
+// ReadFile is the FS interface implementation.
+func (w *ioFSWrapper) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(w.fsys, name)
+}
+
+// Stat is the FS interface implementation.
+func (w *ioFSWrapper) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(w.fsys, name)
+}
+
+// RemoveAll is the FS interface implementation. It always returns ErrReadOnly.
+func (w *ioFSWrapper) RemoveAll(name string) error {
+	return ErrReadOnly{Op: "removeall"}
+}
+
+// Create is the FS interface implementation. It always returns ErrReadOnly.
+func (w *ioFSWrapper) Create(name string) (io.WriteCloser, error) {
+	return nil, ErrReadOnly{Op: "create"}
+}
+
+// MkdirAll is the FS interface implementation. It always returns ErrReadOnly.
+func (w *ioFSWrapper) MkdirAll(name string, perm fs.FileMode) error {
+	return ErrReadOnly{Op: "mkdirall"}
+}
+
+// WriteFile is the FS interface implementation. It always returns ErrReadOnly.
+func (w *ioFSWrapper) WriteFile(name string, data []byte, perm fs.FileMode) (int, error) {
+	return 0, ErrReadOnly{Op: "writefile"}
+}
+
+// Open is the FS interface implementation. It reads the underlying fs.FS's file fully into
+// memory so the result can support seeking, since fs.File itself isn't seekable.
+func (w *ioFSWrapper) Open(name string) (File, error) {
+	rf, err := w.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rf.Close()
+	data, err := ioutil.ReadAll(rf)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := fs.Stat(w.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return &ioFSSeekableFile{name: name, fi: fi, r: bytes.NewReader(data)}, nil
+}
+
+// OpenFile is the FS interface implementation. It always returns ErrReadOnly for any flag
+// other than os.O_RDONLY.
+func (w *ioFSWrapper) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if flag != os.O_RDONLY {
+		return nil, ErrReadOnly{Op: "openfile"}
+	}
+	return w.Open(name)
+}
+
+// ConfirmDir is the FS interface implementation. It confirms name is a directory in the
+// underlying fs.FS and returns it cleaned with path.Clean; the result is not made absolute
+// and no symlinks are resolved.
+func (w *ioFSWrapper) ConfirmDir(name string) (ConfirmedDir, error) {
+	fi, err := fs.Stat(w.fsys, name)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", name)
+	}
+	return ConfirmedDir(path.Clean(name)), nil
+}
+
+// NewTmpConfirmedDir is the FS interface implementation. It always returns ErrReadOnly.
+func (w *ioFSWrapper) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return "", ErrReadOnly{Op: "newtmpconfirmeddir"}
+}
+
+// readDir returns the FileInfo of the direct children of dir in f's tree, in lexical order.
+func (f *FakeFS) readDir(dir string) ([]fs.FileInfo, error) {
+	node := f.lookup(dir)
+	if node == nil || !node.isDir() {
+		return nil, FakeFileNotFound{Filename: dir}
+	}
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	infos := make([]fs.FileInfo, len(names))
+	for i, name := range names {
+		infos[i] = newFakeFI(node.children[name])
+	}
+	return infos, nil
+}
+
+// glob matches pattern against every path in f's tree, walked in lexical order.
+func (f *FakeFS) glob(pattern string) ([]string, error) {
+	var matches []string
+	var matchErr error
+	var walk func(p string, node *fakeNode)
+	walk = func(p string, node *fakeNode) {
+		if p != "" {
+			if ok, err := path.Match(pattern, p); err != nil {
+				matchErr = err
+			} else if ok {
+				matches = append(matches, p)
+			}
+		}
+		if !node.isDir() {
+			return
+		}
+		names := make([]string, 0, len(node.children))
+		for name := range node.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walk(path.Join(p, name), node.children[name])
+		}
+	}
+	walk("", f.root)
+	if matchErr != nil {
+		return nil, matchErr
+	}
+	return matches, nil
+}
@@ -0,0 +1,97 @@
+package sys
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIOFSAdapterOpenDirectory(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/site/index.html", []byte("hi"), 0644).AddFile("/site/a.txt", []byte("a"), 0644)
+
+	f, err := AsIOFS(fsys).Open("/site")
+	if err != nil {
+		t.Fatalf("Open(dir): %v", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if !fi.IsDir() {
+		t.Fatalf("Stat(%q).IsDir() = false, want true", "/site")
+	}
+
+	rdf, ok := f.(fs.ReadDirFile)
+	if !ok {
+		t.Fatalf("Open(dir) result does not implement fs.ReadDirFile")
+	}
+	entries, err := rdf.ReadDir(-1)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ReadDir returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Name() != "a.txt" || entries[1].Name() != "index.html" {
+		t.Errorf("ReadDir entries = [%s, %s], want [a.txt, index.html]", entries[0].Name(), entries[1].Name())
+	}
+}
+
+func TestIOFSAdapterOpenFile(t *testing.T) {
+	fsys := NewFakeFS().AddFile("/a.txt", []byte("hello"), 0644)
+
+	f, err := AsIOFS(fsys).Open("/a.txt")
+	if err != nil {
+		t.Fatalf("Open(file): %v", err)
+	}
+	defer f.Close()
+
+	if _, ok := f.(fs.ReadDirFile); ok {
+		t.Error("Open(file) result implements fs.ReadDirFile, want only fs.File")
+	}
+
+	b := make([]byte, 5)
+	n, err := f.Read(b)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(b[:n]) != "hello" {
+		t.Errorf("Read = %q, want %q", b[:n], "hello")
+	}
+}
+
+func TestFromIOFSReadsAndRejectsWrites(t *testing.T) {
+	mapFS := fstest.MapFS{
+		"a.txt": &fstest.MapFile{Data: []byte("hello"), Mode: 0644},
+	}
+
+	fsys := FromIOFS(mapFS)
+
+	got, err := fsys.ReadFile("a.txt")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("ReadFile = %q, want %q", got, "hello")
+	}
+
+	f, err := fsys.Open("a.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+	b := make([]byte, 5)
+	n, err := f.Read(b)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(b[:n]) != "hello" {
+		t.Errorf("Read = %q, want %q", b[:n], "hello")
+	}
+
+	if _, err := fsys.Create("b.txt"); err == nil {
+		t.Error("Create on a FromIOFS-wrapped fs.FS succeeded, want ErrReadOnly")
+	}
+}
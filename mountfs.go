@@ -0,0 +1,209 @@
+package sys
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// mount associates a path prefix with the FS that should handle paths under it.
+type mount struct {
+	prefix string
+	fs     FS
+}
+
+// MountFS is an FS that composes other FS implementations. Each mounted FS is registered at a
+// path prefix with Mount; operations route to the mount whose prefix is the longest match for
+// the given path, with the path translated to be relative to that mount point before
+// delegation. Paths that don't fall under any mount are served by base.
+type MountFS struct {
+	base   FS
+	mounts []mount
+}
+
+// NewMountFS returns a MountFS with no mounts, whose base FS is base. base handles any path
+// that isn't under a prefix registered with Mount.
+func NewMountFS(base FS) *MountFS {
+	return &MountFS{base: base}
+}
+
+// Mount registers fs to handle every path under prefix. If prefix overlaps with an existing
+// mount, the longer (more specific) prefix wins.
+func (m *MountFS) Mount(prefix string, fs FS) {
+	m.mounts = append(m.mounts, mount{prefix: path.Clean(prefix), fs: fs})
+	sort.SliceStable(m.mounts, func(i, j int) bool {
+		return len(m.mounts[i].prefix) > len(m.mounts[j].prefix)
+	})
+}
+
+// resolve returns the FS and translated path that should handle p, using the longest matching
+// mount, falling back to base if no mount matches.
+func (m *MountFS) resolve(p string) (FS, string) {
+	clean := path.Clean(p)
+	for _, mt := range m.mounts {
+		if mt.prefix != "/" && clean != mt.prefix && !strings.HasPrefix(clean, mt.prefix+"/") {
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(clean, mt.prefix), "/")
+		if rel == "" {
+			rel = "."
+		}
+		return mt.fs, rel
+	}
+	return m.base, p
+}
+
+// ReadFile is the FS interface implementation.
+func (m *MountFS) ReadFile(name string) ([]byte, error) {
+	fs, rel := m.resolve(name)
+	return fs.ReadFile(rel)
+}
+
+// RemoveAll is the FS interface implementation.
+func (m *MountFS) RemoveAll(name string) error {
+	fs, rel := m.resolve(name)
+	return fs.RemoveAll(rel)
+}
+
+// Create is the FS interface implementation.
+func (m *MountFS) Create(name string) (io.WriteCloser, error) {
+	fs, rel := m.resolve(name)
+	return fs.Create(rel)
+}
+
+// Stat is the FS interface implementation.
+func (m *MountFS) Stat(name string) (os.FileInfo, error) {
+	fs, rel := m.resolve(name)
+	return fs.Stat(rel)
+}
+
+// MkdirAll is the FS interface implementation.
+func (m *MountFS) MkdirAll(name string, perm os.FileMode) error {
+	fs, rel := m.resolve(name)
+	return fs.MkdirAll(rel, perm)
+}
+
+// WriteFile is the FS interface implementation.
+func (m *MountFS) WriteFile(name string, data []byte, perm os.FileMode) (int, error) {
+	fs, rel := m.resolve(name)
+	return fs.WriteFile(rel, data, perm)
+}
+
+// Open is the FS interface implementation.
+func (m *MountFS) Open(name string) (File, error) {
+	fs, rel := m.resolve(name)
+	return fs.Open(rel)
+}
+
+// OpenFile is the FS interface implementation.
+func (m *MountFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	fs, rel := m.resolve(name)
+	return fs.OpenFile(rel, flag, perm)
+}
+
+// ConfirmDir is the FS interface implementation. It confirms rel is a directory against
+// whichever FS handles name, but returns name itself, cleaned (not the path relative to the
+// mount point), as the ConfirmedDir, since that's the path callers of m will recognize.
+func (m *MountFS) ConfirmDir(name string) (ConfirmedDir, error) {
+	fs, rel := m.resolve(name)
+	if _, err := fs.ConfirmDir(rel); err != nil {
+		return "", err
+	}
+	return ConfirmedDir(path.Clean(name)), nil
+}
+
+// NewTmpConfirmedDir is the FS interface implementation. It delegates to base, since a
+// temporary directory isn't naturally associated with any particular mount.
+func (m *MountFS) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return m.base.NewTmpConfirmedDir()
+}
+
+// overlayFS is a copy-on-write FS: writes always go to upper, while reads check upper first
+// and fall back to lower. Nothing is copied from lower into upper: a write to a file that
+// exists only in lower creates (or fails to find) the file in upper alone, and RemoveAll
+// doesn't hide files that exist in lower.
+type overlayFS struct {
+	lower FS
+	upper FS
+}
+
+// NewOverlayFS returns an FS that reads from upper, falling back to lower, and writes only to
+// upper, leaving lower untouched. This lets callers layer a writable FS (e.g. a FakeFS in
+// tests, or a scratch directory in production) on top of a read-only or shared one (e.g. an
+// embedded asset FS or RealFS).
+func NewOverlayFS(lower, upper FS) FS {
+	return &overlayFS{lower: lower, upper: upper}
+}
+
+// ReadFile is the FS interface implementation.
+func (o *overlayFS) ReadFile(name string) ([]byte, error) {
+	b, err := o.upper.ReadFile(name)
+	if err == nil {
+		return b, nil
+	}
+	return o.lower.ReadFile(name)
+}
+
+// RemoveAll is the FS interface implementation. It only ever affects upper.
+func (o *overlayFS) RemoveAll(name string) error {
+	return o.upper.RemoveAll(name)
+}
+
+// Create is the FS interface implementation. It only ever writes to upper.
+func (o *overlayFS) Create(name string) (io.WriteCloser, error) {
+	return o.upper.Create(name)
+}
+
+// Stat is the FS interface implementation.
+func (o *overlayFS) Stat(name string) (os.FileInfo, error) {
+	fi, err := o.upper.Stat(name)
+	if err == nil {
+		return fi, nil
+	}
+	return o.lower.Stat(name)
+}
+
+// MkdirAll is the FS interface implementation. It only ever affects upper.
+func (o *overlayFS) MkdirAll(name string, perm os.FileMode) error {
+	return o.upper.MkdirAll(name, perm)
+}
+
+// WriteFile is the FS interface implementation. It only ever writes to upper.
+func (o *overlayFS) WriteFile(name string, data []byte, perm os.FileMode) (int, error) {
+	return o.upper.WriteFile(name, data, perm)
+}
+
+// Open is the FS interface implementation. It checks upper first, falling back to lower.
+func (o *overlayFS) Open(name string) (File, error) {
+	if f, err := o.upper.Open(name); err == nil {
+		return f, nil
+	}
+	return o.lower.Open(name)
+}
+
+// OpenFile is the FS interface implementation. Read-only opens check upper first, falling
+// back to lower; any other flag always goes to upper.
+func (o *overlayFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag == os.O_RDONLY {
+		if f, err := o.upper.OpenFile(name, flag, perm); err == nil {
+			return f, nil
+		}
+		return o.lower.OpenFile(name, flag, perm)
+	}
+	return o.upper.OpenFile(name, flag, perm)
+}
+
+// ConfirmDir is the FS interface implementation.
+func (o *overlayFS) ConfirmDir(name string) (ConfirmedDir, error) {
+	if cd, err := o.upper.ConfirmDir(name); err == nil {
+		return cd, nil
+	}
+	return o.lower.ConfirmDir(name)
+}
+
+// NewTmpConfirmedDir is the FS interface implementation. It only ever affects upper.
+func (o *overlayFS) NewTmpConfirmedDir() (ConfirmedDir, error) {
+	return o.upper.NewTmpConfirmedDir()
+}
@@ -0,0 +1,65 @@
+package sys
+
+import "testing"
+
+func TestMountFSRoutesToLongestPrefix(t *testing.T) {
+	base := NewFakeFS().AddFile("/base.txt", []byte("base"), 0644)
+	data := NewFakeFS().AddFile("/f.txt", []byte("data"), 0644)
+	dataSub := NewFakeFS().AddFile("/g.txt", []byte("data-sub"), 0644)
+
+	m := NewMountFS(base)
+	m.Mount("/data", data)
+	m.Mount("/data/sub", dataSub)
+
+	if got, err := m.ReadFile("/base.txt"); err != nil || string(got) != "base" {
+		t.Errorf("ReadFile(/base.txt) = %q, %v, want %q, nil", got, err, "base")
+	}
+	if got, err := m.ReadFile("/data/f.txt"); err != nil || string(got) != "data" {
+		t.Errorf("ReadFile(/data/f.txt) = %q, %v, want %q, nil", got, err, "data")
+	}
+	if got, err := m.ReadFile("/data/sub/g.txt"); err != nil || string(got) != "data-sub" {
+		t.Errorf("ReadFile(/data/sub/g.txt) = %q, %v, want %q, nil", got, err, "data-sub")
+	}
+}
+
+func TestMountFSRoutesRootMount(t *testing.T) {
+	base := NewFakeFS().AddFile("/base.txt", []byte("base"), 0644)
+	root := NewFakeFS().AddFile("/a.txt", []byte("root"), 0644)
+
+	m := NewMountFS(base)
+	m.Mount("/", root)
+
+	if got, err := m.ReadFile("/a.txt"); err != nil || string(got) != "root" {
+		t.Errorf("ReadFile(/a.txt) = %q, %v, want %q, nil", got, err, "root")
+	}
+}
+
+func TestOverlayFSReadsUpperBeforeLower(t *testing.T) {
+	lower := NewFakeFS().AddFile("/a.txt", []byte("lower-a"), 0644).AddFile("/b.txt", []byte("lower-b"), 0644)
+	upper := NewFakeFS().AddFile("/a.txt", []byte("upper-a"), 0644)
+
+	o := NewOverlayFS(lower, upper)
+
+	if got, err := o.ReadFile("/a.txt"); err != nil || string(got) != "upper-a" {
+		t.Errorf("ReadFile(/a.txt) = %q, %v, want %q, nil", got, err, "upper-a")
+	}
+	if got, err := o.ReadFile("/b.txt"); err != nil || string(got) != "lower-b" {
+		t.Errorf("ReadFile(/b.txt) = %q, %v, want %q, nil", got, err, "lower-b")
+	}
+}
+
+func TestOverlayFSWritesOnlyUpper(t *testing.T) {
+	lower := NewFakeFS()
+	upper := NewFakeFS()
+	o := NewOverlayFS(lower, upper)
+
+	if _, err := o.WriteFile("/c.txt", []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := lower.ReadFile("/c.txt"); err == nil {
+		t.Error("WriteFile leaked through to lower")
+	}
+	if got, err := upper.ReadFile("/c.txt"); err != nil || string(got) != "new" {
+		t.Errorf("upper.ReadFile(/c.txt) = %q, %v, want %q, nil", got, err, "new")
+	}
+}